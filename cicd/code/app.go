@@ -1,24 +1,32 @@
 package main
 
 import (
-  "fmt"
+  "context"
   "os"
-  "time"
+  "os/signal"
+  "syscall"
+
+  "conducto/demo/internal/pretendcmd"
+  "conducto/demo/internal/pretendsvc"
+)
+
+const (
+  binaryName = "app"
+  testCount  = 8
 )
 
-// Simple program that prints message.
-// --test argument invokes pretend tests, that will always pass.
+// This is the app binary. See internal/pretendsvc for what each of its
+// subcommands (test, serve, migrate, version, selfcheck) actually does.
 func main() {
-  if len(os.Args) < 2 {
-    fmt.Println("This is my app binary")
-  } else {
-    if os.Args[1] == "--test" {
-      fmt.Println("Pretending to run app tests...")
-      for i := 0; i < 8; i++ {
-        time.Sleep(time.Second)
-        fmt.Fprintln(os.Stderr, "test", i, "[OK]")
-      }
-      fmt.Println("All tests pass!")
-    }
-  }
+  ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+  defer stop()
+
+  app := pretendcmd.New(binaryName)
+  app.Register(pretendsvc.NewTestCommand(binaryName, testCount))
+  app.Register(pretendsvc.NewServeCommand(binaryName))
+  app.Register(pretendsvc.NewMigrateCommand(binaryName))
+  app.Register(pretendsvc.NewVersionCommand(binaryName))
+  app.Register(pretendsvc.NewSelfcheckCommand(binaryName))
+
+  os.Exit(app.Run(ctx, os.Args[1:]))
 }
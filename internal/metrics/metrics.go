@@ -0,0 +1,78 @@
+// Package metrics tracks counters and a duration histogram for the pretend
+// test runs and renders them in the Prometheus text exposition format.
+package metrics
+
+import (
+  "fmt"
+  "io"
+  "sync"
+  "time"
+)
+
+// buckets are the histogram's upper bounds, in seconds.
+var buckets = []float64{0.5, 1, 2.5, 5, 10}
+
+// Metrics is safe for concurrent use.
+type Metrics struct {
+  mu           sync.Mutex
+  total        uint64
+  failedTotal  uint64
+  bucketCounts []uint64 // parallel to buckets, plus one for +Inf
+  sum          float64
+}
+
+// New returns an empty Metrics.
+func New() *Metrics {
+  return &Metrics{bucketCounts: make([]uint64, len(buckets)+1)}
+}
+
+// RecordTest records the outcome and duration of one test iteration.
+func (m *Metrics) RecordTest(d time.Duration, pass bool) {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+
+  m.total++
+  if !pass {
+    m.failedTotal++
+  }
+
+  seconds := d.Seconds()
+  m.sum += seconds
+  for i, le := range buckets {
+    if seconds <= le {
+      m.bucketCounts[i]++
+    }
+  }
+  m.bucketCounts[len(buckets)]++ // +Inf bucket
+}
+
+// WriteProm renders the current metrics in Prometheus text format.
+func (m *Metrics) WriteProm(w io.Writer) error {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+
+  if _, err := fmt.Fprintf(w, "# HELP pretend_tests_total Total number of pretend test iterations run.\n"+
+    "# TYPE pretend_tests_total counter\n"+
+    "pretend_tests_total %d\n"+
+    "# HELP pretend_tests_failed_total Total number of pretend test iterations that failed.\n"+
+    "# TYPE pretend_tests_failed_total counter\n"+
+    "pretend_tests_failed_total %d\n"+
+    "# HELP pretend_test_duration_seconds Duration of each pretend test iteration.\n"+
+    "# TYPE pretend_test_duration_seconds histogram\n",
+    m.total, m.failedTotal); err != nil {
+    return err
+  }
+
+  for i, le := range buckets {
+    if _, err := fmt.Fprintf(w, "pretend_test_duration_seconds_bucket{le=\"%g\"} %d\n", le, m.bucketCounts[i]); err != nil {
+      return err
+    }
+  }
+  if _, err := fmt.Fprintf(w, "pretend_test_duration_seconds_bucket{le=\"+Inf\"} %d\n"+
+    "pretend_test_duration_seconds_sum %g\n"+
+    "pretend_test_duration_seconds_count %d\n",
+    m.bucketCounts[len(buckets)], m.sum, m.total); err != nil {
+    return err
+  }
+  return nil
+}
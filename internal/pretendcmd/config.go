@@ -0,0 +1,77 @@
+package pretendcmd
+
+import (
+  "encoding/json"
+  "fmt"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+)
+
+// Config holds the overrides a --config=<file> can apply to the test
+// command: how many iterations to run, how long each one sleeps, and what
+// exit code to simulate on failure.
+type Config struct {
+  Iterations *int `json:"iterations" yaml:"iterations"`
+  SleepMS    *int `json:"sleep_ms" yaml:"sleep_ms"`
+  ExitCode   *int `json:"exit_code" yaml:"exit_code"`
+}
+
+// LoadConfig reads a JSON or YAML config file, picked by its extension.
+// Only flat scalar keys are supported; that's all Config needs.
+func LoadConfig(path string) (*Config, error) {
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return nil, fmt.Errorf("pretendcmd: read config %s: %w", path, err)
+  }
+
+  var cfg Config
+  switch strings.ToLower(filepath.Ext(path)) {
+  case ".json":
+    if err := json.Unmarshal(data, &cfg); err != nil {
+      return nil, fmt.Errorf("pretendcmd: parse config %s: %w", path, err)
+    }
+  case ".yaml", ".yml":
+    if err := parseFlatYAML(data, &cfg); err != nil {
+      return nil, fmt.Errorf("pretendcmd: parse config %s: %w", path, err)
+    }
+  default:
+    return nil, fmt.Errorf("pretendcmd: config %s must end in .json, .yaml, or .yml", path)
+  }
+  return &cfg, nil
+}
+
+// parseFlatYAML handles the "key: value" subset of YAML that Config needs,
+// one mapping entry per line. It does not support nesting, lists, or quoted
+// multi-line scalars.
+func parseFlatYAML(data []byte, cfg *Config) error {
+  for _, line := range strings.Split(string(data), "\n") {
+    line = strings.TrimSpace(line)
+    if line == "" || strings.HasPrefix(line, "#") {
+      continue
+    }
+    key, value, ok := strings.Cut(line, ":")
+    if !ok {
+      return fmt.Errorf("expected \"key: value\", got %q", line)
+    }
+    key = strings.TrimSpace(key)
+    value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+    n, err := strconv.Atoi(value)
+    if err != nil {
+      return fmt.Errorf("field %q: %q is not an integer", key, value)
+    }
+    switch key {
+    case "iterations":
+      cfg.Iterations = &n
+    case "sleep_ms":
+      cfg.SleepMS = &n
+    case "exit_code":
+      cfg.ExitCode = &n
+    default:
+      return fmt.Errorf("unknown field %q", key)
+    }
+  }
+  return nil
+}
@@ -0,0 +1,81 @@
+// Package pretendcmd is a tiny subcommand registry shared by the app and
+// auth binaries, so each one's main function can dispatch to named commands (test,
+// serve, migrate, version, selfcheck) instead of an ad-hoc os.Args switch.
+package pretendcmd
+
+import (
+  "context"
+  "flag"
+  "fmt"
+  "os"
+)
+
+// Command is one subcommand. Flags is parsed against the arguments that
+// follow the subcommand name before Run is called.
+type Command struct {
+  Name  string
+  Short string
+  Flags *flag.FlagSet
+  Run   func(ctx context.Context, args []string) int
+}
+
+// App is a binary's set of registered subcommands.
+type App struct {
+  Name     string
+  commands []*Command
+  byName   map[string]*Command
+}
+
+// New returns an empty App for the binary called name.
+func New(name string) *App {
+  return &App{Name: name, byName: make(map[string]*Command)}
+}
+
+// Register adds cmd to the app. Commands are listed in --help in the order
+// they were registered.
+func (a *App) Register(cmd *Command) {
+  a.commands = append(a.commands, cmd)
+  a.byName[cmd.Name] = cmd
+}
+
+// Run dispatches args[0] to the matching command and returns its exit code.
+// It handles "--help"/"-h" at both the app and command level.
+func (a *App) Run(ctx context.Context, args []string) int {
+  if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+    a.printHelp()
+    return 0
+  }
+
+  cmd, ok := a.byName[args[0]]
+  if !ok {
+    fmt.Fprintf(os.Stderr, "%s: unknown command %q\n\n", a.Name, args[0])
+    a.printHelp()
+    return 1
+  }
+
+  rest := args[1:]
+  for _, r := range rest {
+    if r == "--help" || r == "-h" {
+      fmt.Printf("Usage: %s %s [flags]\n\n", a.Name, cmd.Name)
+      if cmd.Flags != nil {
+        cmd.Flags.PrintDefaults()
+      }
+      return 0
+    }
+  }
+
+  if cmd.Flags != nil {
+    if err := cmd.Flags.Parse(rest); err != nil {
+      return 1
+    }
+    rest = cmd.Flags.Args()
+  }
+  return cmd.Run(ctx, rest)
+}
+
+func (a *App) printHelp() {
+  fmt.Printf("Usage: %s <command> [flags]\n\nCommands:\n", a.Name)
+  for _, c := range a.commands {
+    fmt.Printf("  %-10s %s\n", c.Name, c.Short)
+  }
+}
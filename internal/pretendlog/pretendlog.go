@@ -0,0 +1,74 @@
+// Package pretendlog is a minimal structured logger for the pretend
+// app/auth binaries, so pipeline consumers can parse their output reliably.
+package pretendlog
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "time"
+)
+
+// Format selects how log entries are rendered.
+type Format string
+
+const (
+  FormatJSON Format = "json"
+  FormatText Format = "text"
+)
+
+// Logger writes one log line per call, either as JSON or as plain text.
+type Logger struct {
+  Component string
+  Format    Format
+  Out       io.Writer
+}
+
+// New returns a Logger for component, writing to out in the given format.
+func New(component string, format Format, out io.Writer) *Logger {
+  return &Logger{Component: component, Format: format, Out: out}
+}
+
+type entry struct {
+  Ts        string `json:"ts"`
+  Level     string `json:"level"`
+  Component string `json:"component"`
+  Msg       string `json:"msg"`
+  TestIndex *int   `json:"test_index,omitempty"`
+}
+
+// Log writes one entry at level with msg. testIndex is omitted when < 0.
+func (l *Logger) Log(level, msg string, testIndex int) {
+  e := entry{
+    Ts:        time.Now().UTC().Format(time.RFC3339Nano),
+    Level:     level,
+    Component: l.Component,
+    Msg:       msg,
+  }
+  if testIndex >= 0 {
+    e.TestIndex = &testIndex
+  }
+
+  if l.Format == FormatJSON {
+    json.NewEncoder(l.Out).Encode(e)
+    return
+  }
+
+  if e.TestIndex != nil {
+    fmt.Fprintf(l.Out, "%s [%s] %s: %s (test_index=%d)\n", e.Ts, level, l.Component, msg, testIndex)
+  } else {
+    fmt.Fprintf(l.Out, "%s [%s] %s: %s\n", e.Ts, level, l.Component, msg)
+  }
+}
+
+// Info logs msg at info level with no associated test index.
+func (l *Logger) Info(msg string) { l.Log("info", msg, -1) }
+
+// InfoTest logs msg at info level for a specific test iteration.
+func (l *Logger) InfoTest(msg string, testIndex int) { l.Log("info", msg, testIndex) }
+
+// Error logs msg at error level with no associated test index.
+func (l *Logger) Error(msg string) { l.Log("error", msg, -1) }
+
+// ErrorTest logs msg at error level for a specific test iteration.
+func (l *Logger) ErrorTest(msg string, testIndex int) { l.Log("error", msg, testIndex) }
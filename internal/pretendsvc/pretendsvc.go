@@ -0,0 +1,317 @@
+// Package pretendsvc builds the test/serve/migrate/version/selfcheck
+// pretendcmd.Command implementations shared by the app and auth binaries,
+// so each binary's main function (cicd/code/app.go, cicd/code/auth.go) only
+// needs to register them under its own binary name and iteration count.
+package pretendsvc
+
+import (
+  "context"
+  "flag"
+  "fmt"
+  "net/http"
+  "os"
+  "time"
+
+  "conducto/demo/internal/metrics"
+  "conducto/demo/internal/pretendcmd"
+  "conducto/demo/internal/pretendlog"
+  "conducto/demo/internal/scenario"
+  "conducto/demo/internal/testreport"
+)
+
+const version = "0.1.0-demo"
+
+// NewTestCommand returns the "test" subcommand, which runs testCount
+// pretend test iterations unless overridden by --config. With --scenario,
+// the scenario file's tests are run instead and --config is ignored.
+func NewTestCommand(binaryName string, testCount int) *pretendcmd.Command {
+  fs := flag.NewFlagSet("test", flag.ExitOnError)
+  report := fs.String("report", "", "emit a machine-readable report as <format>:<path>, format is junit, tap, or text")
+  failAt := fs.Int("fail-at", -1, "index of the test iteration to fail, -1 means never fail")
+  failWith := fs.String("fail-with", "simulated failure", "failure message to report for the --fail-at iteration")
+  logFormat := fs.String("log-format", "text", "log output format: json or text")
+  configPath := fs.String("config", "", "YAML/JSON file overriding iterations, sleep_ms, and exit_code")
+  scenarioPath := fs.String("scenario", "", "JSON scenario file driving per-test duration, flake, and failure behavior")
+
+  return &pretendcmd.Command{
+    Name:  "test",
+    Short: "run pretend tests",
+    Flags: fs,
+    Run: func(ctx context.Context, args []string) int {
+      logger := pretendlog.New(binaryName, pretendlog.Format(*logFormat), os.Stdout)
+      m := metrics.New()
+
+      if *scenarioPath != "" {
+        return runScenarioTests(ctx, binaryName, *scenarioPath, *report, logger, m)
+      }
+
+      iterations := testCount
+      sleep := time.Second
+      exitCode := 1
+
+      if *configPath != "" {
+        cfg, err := pretendcmd.LoadConfig(*configPath)
+        if err != nil {
+          fmt.Fprintln(os.Stderr, err)
+          return 1
+        }
+        if cfg.Iterations != nil {
+          iterations = *cfg.Iterations
+        }
+        if cfg.SleepMS != nil {
+          sleep = time.Duration(*cfg.SleepMS) * time.Millisecond
+        }
+        if cfg.ExitCode != nil {
+          exitCode = *cfg.ExitCode
+        }
+      }
+
+      logger.Info(fmt.Sprintf("pretending to run %s tests", binaryName))
+      results := make([]testreport.Result, iterations)
+      for i := 0; i < iterations; i++ {
+        start := time.Now()
+        select {
+        case <-ctx.Done():
+          logger.ErrorTest("test run cancelled", i)
+          return exitCode
+        case <-time.After(sleep):
+        }
+        d := time.Since(start)
+
+        r := testreport.Result{Index: i, Name: fmt.Sprintf("test-%d", i), Duration: d, Pass: true}
+        if *failAt == i {
+          r.Pass = false
+          r.Message = *failWith
+          logger.ErrorTest(*failWith, i)
+        } else {
+          logger.InfoTest("test passed", i)
+        }
+        m.RecordTest(d, r.Pass)
+        results[i] = r
+      }
+
+      if *report != "" {
+        format, path, err := testreport.ParseReportFlag(*report)
+        if err != nil {
+          logger.Error(err.Error())
+          return 1
+        }
+        if err := testreport.Write(format, path, binaryName, results); err != nil {
+          logger.Error(err.Error())
+          return 1
+        }
+      }
+
+      for _, r := range results {
+        if !r.Pass {
+          logger.Error("tests failed")
+          return exitCode
+        }
+      }
+      logger.Info("all tests pass")
+      return 0
+    },
+  }
+}
+
+// runScenarioTests drives the test loop from a --scenario file instead of a
+// fixed iteration count, simulating pass/flake/fail outcomes per test.
+func runScenarioTests(ctx context.Context, binaryName, scenarioPath, report string, logger *pretendlog.Logger, m *metrics.Metrics) int {
+  s, err := scenario.Load(scenarioPath)
+  if err != nil {
+    logger.Error(err.Error())
+    return 1
+  }
+  attempt := scenario.Attempt()
+
+  logger.Info(fmt.Sprintf("pretending to run %s tests from scenario %s (attempt %d)", binaryName, scenarioPath, attempt))
+  results := make([]testreport.Result, len(s.Tests))
+  for i, t := range s.Tests {
+    start := time.Now()
+    select {
+    case <-ctx.Done():
+      logger.ErrorTest("test run cancelled", i)
+      return 1
+    case <-time.After(time.Duration(t.DurationMS) * time.Millisecond):
+    }
+    d := time.Since(start)
+
+    r := testreport.Result{Index: i, Name: t.Name, Duration: d, Pass: !t.Fails(attempt)}
+    results[i] = r
+    m.RecordTest(d, r.Pass)
+
+    if r.Pass {
+      logger.InfoTest(fmt.Sprintf("%s passed", t.Name), i)
+      continue
+    }
+
+    r.Message = t.Stderr
+    results[i] = r
+    logger.ErrorTest(fmt.Sprintf("%s failed", t.Name), i)
+
+    if report != "" {
+      if format, path, err := testreport.ParseReportFlag(report); err == nil {
+        testreport.Write(format, path, binaryName, results[:i+1])
+      }
+    }
+    if t.Stderr != "" {
+      fmt.Fprintln(os.Stderr, t.Stderr)
+    }
+    return t.ExitCodeOrDefault()
+  }
+
+  if report != "" {
+    format, path, err := testreport.ParseReportFlag(report)
+    if err != nil {
+      logger.Error(err.Error())
+      return 1
+    }
+    if err := testreport.Write(format, path, binaryName, results); err != nil {
+      logger.Error(err.Error())
+      return 1
+    }
+  }
+
+  logger.Info("all tests pass")
+  return 0
+}
+
+// NewServeCommand returns the "serve" subcommand, which runs the binary as
+// a long-lived service exposing /healthz, /readyz, and /metrics.
+func NewServeCommand(binaryName string) *pretendcmd.Command {
+  fs := flag.NewFlagSet("serve", flag.ExitOnError)
+  addr := fs.String("addr", ":8080", "address to serve /healthz, /readyz, and /metrics on")
+  logFormat := fs.String("log-format", "text", "log output format: json or text")
+
+  return &pretendcmd.Command{
+    Name:  "serve",
+    Short: "run as a long-lived service with health and metrics endpoints",
+    Flags: fs,
+    Run: func(ctx context.Context, args []string) int {
+      logger := pretendlog.New(binaryName, pretendlog.Format(*logFormat), os.Stdout)
+      m := metrics.New()
+
+      ready := make(chan struct{})
+      go func() {
+        time.Sleep(2 * time.Second)
+        close(ready)
+        logger.Info("ready")
+      }()
+
+      mux := http.NewServeMux()
+      mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+        fmt.Fprintln(w, "ok")
+      })
+      mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+        select {
+        case <-ready:
+          w.WriteHeader(http.StatusOK)
+          fmt.Fprintln(w, "ready")
+        default:
+          w.WriteHeader(http.StatusServiceUnavailable)
+          fmt.Fprintln(w, "not ready")
+        }
+      })
+      mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+        m.WriteProm(w)
+      })
+
+      srv := &http.Server{Addr: *addr, Handler: mux}
+
+      go func() {
+        <-ctx.Done()
+        logger.Info("shutting down")
+        shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        srv.Shutdown(shutdownCtx)
+      }()
+
+      go runPretendWorkload(ctx, logger, m)
+
+      logger.Info(fmt.Sprintf("serving on %s", *addr))
+      if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+        logger.Error(err.Error())
+        return 1
+      }
+      return 0
+    },
+  }
+}
+
+// runPretendWorkload simulates a long-running node doing periodic work, so
+// /metrics has something to report while the service is up.
+func runPretendWorkload(ctx context.Context, logger *pretendlog.Logger, m *metrics.Metrics) {
+  i := 0
+  ticker := time.NewTicker(2 * time.Second)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+      m.RecordTest(2*time.Second, true)
+      logger.InfoTest("pretend workload iteration", i)
+      i++
+    }
+  }
+}
+
+// NewMigrateCommand returns the "migrate" subcommand, a pretend schema
+// migration that exists to demonstrate a one-shot pipeline node.
+func NewMigrateCommand(binaryName string) *pretendcmd.Command {
+  fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+  logFormat := fs.String("log-format", "text", "log output format: json or text")
+
+  return &pretendcmd.Command{
+    Name:  "migrate",
+    Short: "run a pretend schema migration",
+    Flags: fs,
+    Run: func(ctx context.Context, args []string) int {
+      logger := pretendlog.New(binaryName, pretendlog.Format(*logFormat), os.Stdout)
+      logger.Info("applying pretend migrations")
+      select {
+      case <-ctx.Done():
+        logger.Error("migration cancelled")
+        return 1
+      case <-time.After(time.Second):
+      }
+      logger.Info("migrations applied")
+      return 0
+    },
+  }
+}
+
+// NewVersionCommand returns the "version" subcommand.
+func NewVersionCommand(binaryName string) *pretendcmd.Command {
+  return &pretendcmd.Command{
+    Name:  "version",
+    Short: "print the binary's version",
+    Run: func(ctx context.Context, args []string) int {
+      fmt.Printf("%s version %s\n", binaryName, version)
+      return 0
+    },
+  }
+}
+
+// NewSelfcheckCommand returns the "selfcheck" subcommand, a fast sanity
+// check that the binary itself is runnable (flags parse, clock works).
+func NewSelfcheckCommand(binaryName string) *pretendcmd.Command {
+  fs := flag.NewFlagSet("selfcheck", flag.ExitOnError)
+  logFormat := fs.String("log-format", "text", "log output format: json or text")
+
+  return &pretendcmd.Command{
+    Name:  "selfcheck",
+    Short: "check that the binary runs (flags parse, clock works)",
+    Flags: fs,
+    Run: func(ctx context.Context, args []string) int {
+      logger := pretendlog.New(binaryName, pretendlog.Format(*logFormat), os.Stdout)
+      if time.Now().IsZero() {
+        logger.Error("selfcheck failed: clock unavailable")
+        return 1
+      }
+      logger.Info("selfcheck ok")
+      return 0
+    },
+  }
+}
@@ -0,0 +1,88 @@
+// Package scenario loads the JSON documents that drive the "test"
+// subcommand's --scenario flag, so pipelines can demonstrate retry-on-flake
+// and fail-fast behavior without recompiling the pretend binaries.
+package scenario
+
+import (
+  "encoding/json"
+  "fmt"
+  "math/rand"
+  "os"
+)
+
+// Outcome is what a Test should do when it runs.
+type Outcome string
+
+const (
+  OutcomePass  Outcome = "pass"
+  OutcomeFlake Outcome = "flake"
+  OutcomeFail  Outcome = "fail"
+)
+
+// Test is one entry in a Scenario's test list.
+type Test struct {
+  Name       string  `json:"name"`
+  DurationMS int     `json:"duration_ms"`
+  Outcome    Outcome `json:"outcome"`
+  FlakeSeed  int64   `json:"flake_seed"`
+  FlakeRate  float64 `json:"flake_rate"`
+  Stderr     string  `json:"stderr"`
+  ExitCode   int     `json:"exit_code"`
+}
+
+// Scenario is the top-level document loaded from a --scenario file.
+type Scenario struct {
+  Tests []Test `json:"tests"`
+}
+
+// Load reads and parses a scenario file.
+func Load(path string) (*Scenario, error) {
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return nil, fmt.Errorf("scenario: read %s: %w", path, err)
+  }
+  var s Scenario
+  if err := json.Unmarshal(data, &s); err != nil {
+    return nil, fmt.Errorf("scenario: parse %s: %w", path, err)
+  }
+  return &s, nil
+}
+
+// Fails reports whether t should fail on the given attempt (attempt counts
+// from 0). "fail" always fails; "flake" fails with FlakeRate probability,
+// deterministically per attempt via FlakeSeed so retries can eventually
+// pass; "pass" never fails.
+func (t Test) Fails(attempt int) bool {
+  switch t.Outcome {
+  case OutcomeFail:
+    return true
+  case OutcomeFlake:
+    r := rand.New(rand.NewSource(t.FlakeSeed + int64(attempt)))
+    return r.Float64() < t.FlakeRate
+  default:
+    return false
+  }
+}
+
+// ExitCodeOrDefault returns t.ExitCode, or 1 if it was left unset.
+func (t Test) ExitCodeOrDefault() int {
+  if t.ExitCode == 0 {
+    return 1
+  }
+  return t.ExitCode
+}
+
+// Attempt reads the current retry attempt from $CONDUCTO_ATTEMPT, the
+// environment variable Conducto pipelines set on each retry. It defaults
+// to 0 when unset or unparsable.
+func Attempt() int {
+  v := os.Getenv("CONDUCTO_ATTEMPT")
+  if v == "" {
+    return 0
+  }
+  var n int
+  if _, err := fmt.Sscanf(v, "%d", &n); err != nil {
+    return 0
+  }
+  return n
+}
@@ -0,0 +1,162 @@
+// Package testreport renders the results of the pretend "test" subcommand
+// runs of cicd/code/app.go and cicd/code/auth.go as machine-readable CI output.
+package testreport
+
+import (
+  "encoding/xml"
+  "fmt"
+  "io"
+  "os"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Format selects which machine-readable layout Write emits.
+type Format string
+
+const (
+  FormatJUnit Format = "junit"
+  FormatTAP   Format = "tap"
+  FormatText  Format = "text"
+)
+
+// Result is one test iteration's outcome.
+type Result struct {
+  Index    int
+  Name     string
+  Duration time.Duration
+  Pass     bool
+  Message  string
+}
+
+// ParseReportFlag splits a "--report=<format>:<path>" value into its format
+// and path parts. Both parts are required; a missing or unknown format is
+// an error.
+func ParseReportFlag(value string) (Format, string, error) {
+  parts := strings.SplitN(value, ":", 2)
+  if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+    return "", "", fmt.Errorf("testreport: --report value %q must be <format>:<path>", value)
+  }
+  format := Format(parts[0])
+  switch format {
+  case FormatJUnit, FormatTAP, FormatText:
+    return format, parts[1], nil
+  default:
+    return "", "", fmt.Errorf("testreport: unknown report format %q", parts[0])
+  }
+}
+
+// Write renders results in the given format and saves them to path.
+func Write(format Format, path, suite string, results []Result) error {
+  f, err := os.Create(path)
+  if err != nil {
+    return fmt.Errorf("testreport: create %s: %w", path, err)
+  }
+  defer f.Close()
+
+  switch format {
+  case FormatJUnit:
+    return writeJUnit(f, suite, results)
+  case FormatTAP:
+    return writeTAP(f, results)
+  case FormatText:
+    return writeText(f, suite, results)
+  default:
+    return fmt.Errorf("testreport: unknown report format %q", format)
+  }
+}
+
+type junitTestsuite struct {
+  XMLName  xml.Name        `xml:"testsuite"`
+  Name     string          `xml:"name,attr"`
+  Tests    int             `xml:"tests,attr"`
+  Failures int             `xml:"failures,attr"`
+  Time     string          `xml:"time,attr"`
+  Cases    []junitTestcase `xml:"testcase"`
+}
+
+type junitTestcase struct {
+  Classname string        `xml:"classname,attr"`
+  Name      string        `xml:"name,attr"`
+  Time      string        `xml:"time,attr"`
+  Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+  Message string `xml:"message,attr"`
+}
+
+func writeJUnit(w io.Writer, suite string, results []Result) error {
+  suiteT := junitTestsuite{
+    Name:  suite,
+    Tests: len(results),
+    Cases: make([]junitTestcase, len(results)),
+  }
+  var total time.Duration
+  for i, r := range results {
+    total += r.Duration
+    tc := junitTestcase{
+      Classname: suite,
+      Name:      fmt.Sprintf("test-%d", r.Index),
+      Time:      formatSeconds(r.Duration),
+    }
+    if !r.Pass {
+      suiteT.Failures++
+      tc.Failure = &junitFailure{Message: r.Message}
+    }
+    suiteT.Cases[i] = tc
+  }
+  suiteT.Time = formatSeconds(total)
+
+  if _, err := io.WriteString(w, xml.Header); err != nil {
+    return err
+  }
+  enc := xml.NewEncoder(w)
+  enc.Indent("", "  ")
+  if err := enc.Encode(suiteT); err != nil {
+    return err
+  }
+  _, err := io.WriteString(w, "\n")
+  return err
+}
+
+func writeTAP(w io.Writer, results []Result) error {
+  if _, err := fmt.Fprintf(w, "1..%d\n", len(results)); err != nil {
+    return err
+  }
+  for _, r := range results {
+    n := r.Index + 1
+    if r.Pass {
+      if _, err := fmt.Fprintf(w, "ok %d - test-%d # duration_ms=%d\n", n, r.Index, r.Duration.Milliseconds()); err != nil {
+        return err
+      }
+      continue
+    }
+    msg := r.Message
+    if msg == "" {
+      msg = "failed"
+    }
+    if _, err := fmt.Fprintf(w, "not ok %d - test-%d # duration_ms=%d %s\n", n, r.Index, r.Duration.Milliseconds(), msg); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func writeText(w io.Writer, suite string, results []Result) error {
+  for _, r := range results {
+    status := "OK"
+    if !r.Pass {
+      status = "FAIL"
+    }
+    if _, err := fmt.Fprintf(w, "%s test-%d [%s] duration_ms=%d\n", suite, r.Index, status, r.Duration.Milliseconds()); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func formatSeconds(d time.Duration) string {
+  return strconv.FormatFloat(d.Seconds(), 'f', 2, 64)
+}